@@ -0,0 +1,51 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"syscall"
+)
+
+// NewPingChecker wraps a single round-trip liveness probe (typically the
+// storage backend's Ping method) as a Checker.
+func NewPingChecker(name string, ping func(ctx context.Context) error) Checker {
+	return CheckerFunc{CheckerName: name, Fn: ping}
+}
+
+// NewDiskSpaceChecker fails once free space on path drops below minFreeBytes.
+func NewDiskSpaceChecker(name, path string, minFreeBytes uint64) Checker {
+	return CheckerFunc{CheckerName: name, Fn: func(ctx context.Context) error {
+		var stat syscall.Statfs_t
+		if err := syscall.Statfs(path, &stat); err != nil {
+			return err
+		}
+
+		free := uint64(stat.Bavail) * uint64(stat.Bsize)
+		if free < minFreeBytes {
+			return fmt.Errorf("only %d bytes free on %s, want at least %d", free, path, minFreeBytes)
+		}
+		return nil
+	}}
+}
+
+// NewHTTPChecker fails unless a GET to url returns a 2xx status before ctx's deadline.
+func NewHTTPChecker(name, url string) Checker {
+	return CheckerFunc{CheckerName: name, Fn: func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return err
+		}
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return fmt.Errorf("%s returned status %d", url, resp.StatusCode)
+		}
+		return nil
+	}}
+}
@@ -0,0 +1,88 @@
+// Package health provides a small declarative checker/registry pair so
+// orchestrators get an aggregated, actionable readiness signal instead of
+// relying on the TCP listener alone.
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Checker is a single health check.
+type Checker interface {
+	Name() string
+	Check(ctx context.Context) error
+}
+
+// CheckerFunc adapts a plain function to the Checker interface.
+type CheckerFunc struct {
+	CheckerName string
+	Fn          func(ctx context.Context) error
+}
+
+func (f CheckerFunc) Name() string                    { return f.CheckerName }
+func (f CheckerFunc) Check(ctx context.Context) error { return f.Fn(ctx) }
+
+// Result is the outcome of running a single Checker.
+type Result struct {
+	Name     string        `json:"name"`
+	Healthy  bool          `json:"healthy"`
+	Error    string        `json:"error,omitempty"`
+	Duration time.Duration `json:"duration"`
+}
+
+// Registry runs a set of Checkers concurrently, each bounded by timeout.
+type Registry struct {
+	mu       sync.Mutex
+	checkers []Checker
+	timeout  time.Duration
+}
+
+// NewRegistry returns an empty Registry that bounds every Checker to timeout.
+func NewRegistry(timeout time.Duration) *Registry {
+	return &Registry{timeout: timeout}
+}
+
+// Register adds c to the set of checks run by Run.
+func (r *Registry) Register(c Checker) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.checkers = append(r.checkers, c)
+}
+
+// Run executes every registered Checker concurrently and returns one Result
+// per Checker, in registration order.
+func (r *Registry) Run(ctx context.Context) []Result {
+	r.mu.Lock()
+	checkers := make([]Checker, len(r.checkers))
+	copy(checkers, r.checkers)
+	r.mu.Unlock()
+
+	results := make([]Result, len(checkers))
+	var wg sync.WaitGroup
+	for i, c := range checkers {
+		wg.Add(1)
+		go func(i int, c Checker) {
+			defer wg.Done()
+			results[i] = r.runOne(ctx, c)
+		}(i, c)
+	}
+	wg.Wait()
+
+	return results
+}
+
+func (r *Registry) runOne(ctx context.Context, c Checker) Result {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := c.Check(ctx)
+
+	res := Result{Name: c.Name(), Healthy: err == nil, Duration: time.Since(start)}
+	if err != nil {
+		res.Error = err.Error()
+	}
+	return res
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/thearyanahmed/RecipeAPI/health"
+)
+
+const healthCheckTimeout = 3 * time.Second
+
+// newHealthRegistry registers the checks that determine readiness: the
+// storage backend's connectivity (when it exposes Ping), free disk space,
+// and an optional dependent-service HTTP check configured via env.
+func newHealthRegistry() *health.Registry {
+	reg := health.NewRegistry(healthCheckTimeout)
+
+	if pinger, ok := recipes.(interface{ Ping(ctx context.Context) error }); ok {
+		reg.Register(health.NewPingChecker("storage", pinger.Ping))
+	}
+
+	reg.Register(health.NewDiskSpaceChecker("disk", "/", 100*1024*1024))
+
+	if url := os.Getenv("HEALTH_DEPENDENCY_URL"); url != "" {
+		reg.Register(health.NewHTTPChecker("dependency", url))
+	}
+
+	return reg
+}
+
+// LivenessHandler answers /healthz: 200 whenever the process is up.
+func LivenessHandler(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+}
+
+// ReadinessHandler answers /readyz with the aggregated result of every
+// registered check, 503 if any of them failed.
+func ReadinessHandler(reg *health.Registry) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		results := reg.Run(r.Context())
+
+		healthy := true
+		for _, res := range results {
+			if !res.Healthy {
+				healthy = false
+				break
+			}
+		}
+
+		code := http.StatusOK
+		if !healthy {
+			code = http.StatusServiceUnavailable
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(code)
+		json.NewEncoder(w).Encode(map[string]interface{}{"checks": results})
+	}
+}
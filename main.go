@@ -1,65 +1,243 @@
 package main
 
 import (
-	"crypto/tls"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"os/signal"
 	"strconv"
+	"sync"
+	"syscall"
+	"time"
 
-	"github.com/go-redis/redis/v7"
 	"github.com/gorilla/mux"
+
+	"github.com/thearyanahmed/RecipeAPI/store"
 )
 
-var rdb *redis.Client
+var recipes store.RecipeStore
 
-func main() {
-	host := os.Getenv("REDIS_HOST")
-	pass := os.Getenv("REDIS_PASSWORD")
-	insecure, err := strconv.ParseBool(os.Getenv("REDIS_INSECURE_SKIP_VERIFY"))
+var errMissingPage = errors.New("missing page parameter")
+
+const (
+	defaultReadTimeout       = 5 * time.Second
+	defaultReadHeaderTimeout = 5 * time.Second
+	defaultWriteTimeout      = 10 * time.Second
+	defaultIdleTimeout       = 120 * time.Second
+	defaultRequestTimeout    = 10 * time.Second
+	defaultShutdownTimeout   = 15 * time.Second
+)
 
+func main() {
+	var err error
+	recipes, err = store.New(context.Background())
 	if err != nil {
-		fmt.Println("Error parsing boolean:", err)
-		insecure = false
-		fmt.Println("Defaulting to false")
+		log.Fatal("failed to initialise storage backend:", err)
 	}
 
-	rdb = redis.NewClient(&redis.Options{
-		Addr:     host,
-		Password: pass,
-		TLSConfig: &tls.Config{
-			InsecureSkipVerify: insecure, // Only for self-signed certificates
-		},
-	})
-
 	r := mux.NewRouter()
+	r.Use(corsMiddleware)
+	r.Use(timeoutMiddleware(requestTimeoutFromEnv()))
 
 	r.Path("/recipe").Methods("POST").HandlerFunc(CreateHandler)
 	r.Path("/recipe/{id}").Methods("PUT").HandlerFunc(UpdateHandler)
 	r.Path("/recipe/{id}").Methods("GET").HandlerFunc(GetHandler)
+	r.Path("/recipe/{id}").Methods("DELETE").HandlerFunc(DeleteHandler)
 	r.Path("/recipes").Methods("GET").HandlerFunc(ListHandler)
+	r.Path("/recipes/search").Methods("GET").HandlerFunc(SearchHandler)
+	r.Path("/healthz").Methods("GET").HandlerFunc(LivenessHandler)
+	r.Path("/readyz").Methods("GET").HandlerFunc(ReadinessHandler(newHealthRegistry()))
+	r.PathPrefix("/").Methods("OPTIONS").HandlerFunc(OptionsHandler)
+
+	srv := &http.Server{
+		Addr:              ":8080",
+		Handler:           r,
+		ReadTimeout:       durationFromEnv("HTTP_READ_TIMEOUT", defaultReadTimeout),
+		ReadHeaderTimeout: durationFromEnv("HTTP_READ_HEADER_TIMEOUT", defaultReadHeaderTimeout),
+		WriteTimeout:      durationFromEnv("HTTP_WRITE_TIMEOUT", defaultWriteTimeout),
+		IdleTimeout:       durationFromEnv("HTTP_IDLE_TIMEOUT", defaultIdleTimeout),
+	}
+
+	fmt.Println("will be starting on :8080 using backend", os.Getenv("STORAGE_BACKEND"))
+
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal(err)
+		}
+	}()
+
+	waitForShutdown(srv)
+}
+
+// waitForShutdown blocks until SIGINT/SIGTERM is received, then drains
+// in-flight requests for up to defaultShutdownTimeout before returning.
+func waitForShutdown(srv *http.Server) {
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
+	<-stop
+
+	fmt.Println("shutting down gracefully")
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultShutdownTimeout)
+	defer cancel()
+
+	if err := srv.Shutdown(ctx); err != nil {
+		fmt.Println("error during shutdown:", err)
+	}
+}
+
+// durationFromEnv parses a Go duration from the named env var, falling back
+// to def when unset or invalid.
+func durationFromEnv(name string, def time.Duration) time.Duration {
+	v := os.Getenv(name)
+	if v == "" {
+		return def
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		fmt.Printf("invalid %s %q, using default %s\n", name, v, def)
+		return def
+	}
+	return d
+}
+
+func requestTimeoutFromEnv() time.Duration {
+	return durationFromEnv("HTTP_REQUEST_TIMEOUT", defaultRequestTimeout)
+}
+
+// timeoutMiddleware bounds every request to d, responding 504 if the handler
+// hasn't finished in time. http.TimeoutHandler isn't used here because it
+// always answers with 503 regardless of the body written, which doesn't
+// match the 504 this API wants to report.
+func timeoutMiddleware(d time.Duration) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, cancel := context.WithTimeout(r.Context(), d)
+			defer cancel()
+
+			tw := &timeoutWriter{ResponseWriter: w}
+			done := make(chan struct{})
+
+			go func() {
+				next.ServeHTTP(tw, r.WithContext(ctx))
+				close(done)
+			}()
+
+			select {
+			case <-done:
+			case <-ctx.Done():
+				if tw.markTimedOut() {
+					sendResponse(w, http.StatusGatewayTimeout, "request timed out", nil)
+				}
+			}
+		})
+	}
+}
 
-	fmt.Println("will be starting using ", host, pass)
-	log.Fatal(http.ListenAndServe(":8080", r))
+// timeoutWriter lets timeoutMiddleware silence a handler that's still
+// running after the deadline, so it can't write to the ResponseWriter once
+// the middleware has already sent the 504 itself.
+type timeoutWriter struct {
+	http.ResponseWriter
+	mu          sync.Mutex
+	timedOut    bool
+	wroteHeader bool
+}
+
+func (tw *timeoutWriter) WriteHeader(code int) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.timedOut || tw.wroteHeader {
+		return
+	}
+	tw.wroteHeader = true
+	tw.ResponseWriter.WriteHeader(code)
+}
+
+func (tw *timeoutWriter) Write(b []byte) (int, error) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.timedOut {
+		return len(b), nil
+	}
+	tw.wroteHeader = true
+	return tw.ResponseWriter.Write(b)
+}
+
+// markTimedOut marks the writer as timed out, provided the handler hasn't
+// already written a response. It reports whether the timeout response
+// should be sent.
+func (tw *timeoutWriter) markTimedOut() bool {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.wroteHeader {
+		return false
+	}
+	tw.timedOut = true
+	return true
+}
+
+// APIResponse is the envelope the recipe handlers respond with. The health
+// and OPTIONS handlers do not use it.
+type APIResponse struct {
+	Status Status      `json:"status"`
+	Data   interface{} `json:"data,omitempty"`
+}
+
+// Status carries the HTTP status code alongside a human readable message.
+type Status struct {
+	Code int    `json:"code"`
+	Msg  string `json:"msg"`
+}
+
+// sendResponse writes an APIResponse envelope as JSON with the given status code.
+func sendResponse(w http.ResponseWriter, code int, msg string, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+
+	json.NewEncoder(w).Encode(APIResponse{
+		Status: Status{Code: code, Msg: msg},
+		Data:   data,
+	})
+}
+
+// corsMiddleware allows browser clients on other origins to call the API.
+func corsMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+		next.ServeHTTP(w, r)
+	})
+}
+
+// OptionsHandler answers CORS preflight requests.
+func OptionsHandler(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNoContent)
 }
 
 func CreateHandler(w http.ResponseWriter, r *http.Request) {
 
-	var recipe recipe
+	var recipe store.Recipe
 	err := json.NewDecoder(r.Body).Decode(&recipe)
 	if err != nil {
 		handleError(w, err)
 		return
 	}
 
-	err = recipe.save(rdb)
+	recipe.ID = 0 // creating a recipe always assigns a fresh id; a client-supplied id is ignored
+
+	err = recipes.Save(r.Context(), &recipe, false)
 	if err != nil {
 		handleError(w, err)
 		return
 	}
+
+	sendResponse(w, http.StatusCreated, "recipe created", recipe)
 }
 
 func UpdateHandler(w http.ResponseWriter, r *http.Request) {
@@ -71,7 +249,7 @@ func UpdateHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var recipe recipe
+	var recipe store.Recipe
 	err = json.NewDecoder(r.Body).Decode(&recipe)
 	if err != nil {
 		handleError(w, err)
@@ -79,11 +257,13 @@ func UpdateHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	recipe.ID = int64(idInt)
-	err = recipe.save(rdb)
+	err = recipes.Save(r.Context(), &recipe, true)
 	if err != nil {
 		handleError(w, err)
 		return
 	}
+
+	sendResponse(w, http.StatusOK, "recipe updated", recipe)
 }
 
 func GetHandler(w http.ResponseWriter, r *http.Request) {
@@ -95,30 +275,37 @@ func GetHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var recipe = &recipe{}
+	recipe, err := recipes.Load(r.Context(), int64(idInt))
+	if err != nil {
+		handleError(w, err)
+		return
+	}
 
-	err = recipe.load(int64(idInt), rdb)
+	sendResponse(w, http.StatusOK, "ok", recipe)
+}
 
-	fmt.Printf("err %v\n", err)
+func DeleteHandler(w http.ResponseWriter, r *http.Request) {
 
+	id := mux.Vars(r)["id"]
+	idInt, err := strconv.Atoi(id)
 	if err != nil {
 		handleError(w, err)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	err = json.NewEncoder(w).Encode(recipe)
-	if err != nil {
+	if err := recipes.Delete(r.Context(), int64(idInt)); err != nil {
 		handleError(w, err)
 		return
 	}
+
+	sendResponse(w, http.StatusOK, "recipe deleted", nil)
 }
 
 func ListHandler(w http.ResponseWriter, r *http.Request) {
 
 	pageParam, ok := r.URL.Query()["page"]
 	if !ok {
-		handleError(w, errors.New("missing page parameter"))
+		handleError(w, errMissingPage)
 		return
 	}
 
@@ -128,165 +315,45 @@ func ListHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	l, err := list(page, rdb)
+	l, err := recipes.List(r.Context(), page)
 	if err != nil {
 		handleError(w, err)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	err = json.NewEncoder(w).Encode(l)
-	if err != nil {
-		handleError(w, err)
-		return
-	}
+	sendResponse(w, http.StatusOK, "ok", l)
 }
 
+// handleError maps a domain error to the appropriate HTTP status code and
+// writes it through the APIResponse envelope.
 func handleError(w http.ResponseWriter, err error) {
-	w.WriteHeader(http.StatusBadRequest)
-	w.Write([]byte(err.Error()))
-}
-
-type recipe struct {
-	ID          int64    `json:"id"`
-	Title       string   `json:"title"`
-	Difficulty  string   `json:"difficulty,omitempty"`
-	PrepPeriod  string   `json:"prep_period,omitempty"`
-	Method      string   `json:"method,omitempty"`
-	Categories  []string `json:"categories,omitempty"`
-	Ingredients []string `json:"ingredients,omitempty"`
-	Images      []string `json:"images,omitempty"`
-}
-
-// save used for Create or Update
-func (r *recipe) save(c *redis.Client) error {
-	var save bool
-
-	if r.ID == 0 {
-		save = true
-		id, err := c.Incr("recipe_id").Result()
-		if err != nil {
-			return err
-		}
-		r.ID = id
+	switch {
+	case errors.Is(err, store.ErrNotFound):
+		sendResponse(w, http.StatusNotFound, err.Error(), nil)
+	case errors.Is(err, store.ErrDuplicate):
+		sendResponse(w, http.StatusConflict, err.Error(), nil)
+	case isBadRequest(err):
+		sendResponse(w, http.StatusBadRequest, err.Error(), nil)
+	default:
+		sendResponse(w, http.StatusInternalServerError, err.Error(), nil)
 	}
-
-	_, err := c.TxPipelined(func(pipe redis.Pipeliner) error {
-		if save {
-			if err := pipe.RPush("recipes", r.ID).Err(); err != nil {
-				return err
-			}
-		}
-		pipe.HMSet(fmt.Sprintf("recipe:%d", r.ID),
-			"id", r.ID,
-			"title", r.Title,
-			"difficulty", r.Difficulty,
-			"prep_period", r.PrepPeriod,
-			"method", r.Method,
-		)
-
-		saveList := func(recipeId int64, name string, values []string, c *redis.Client, pipe redis.Pipeliner) {
-			if values == nil {
-				return
-			}
-			key := fmt.Sprintf("recipe:%d:%s", recipeId, name)
-			if c.Exists(key).Val() == 1 {
-				return
-			}
-			pipe.RPush(key, values)
-		}
-
-		saveList(r.ID, "categories", r.Categories, c, pipe)
-		saveList(r.ID, "ingredients", r.Ingredients, c, pipe)
-		saveList(r.ID, "images", r.Images, c, pipe)
-
-		return nil
-	})
-
-	return err
 }
 
-func (r *recipe) load(id int64, c *redis.Client) error {
-	if id <= 0 {
-		return errors.New("invalid id")
+// isBadRequest reports whether err originates from decoding client input
+// (a JSON syntax or type error, a non-numeric or invalid id, or a
+// missing/invalid page) rather than from a storage failure. An empty or
+// truncated request body (io.EOF or io.ErrUnexpectedEOF from the decoder) is
+// not recognised here and is reported as a 500.
+func isBadRequest(err error) bool {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	var numErr *strconv.NumError
+	switch {
+	case errors.As(err, &syntaxErr), errors.As(err, &typeErr), errors.As(err, &numErr):
+		return true
+	case errors.Is(err, store.ErrInvalidID), errors.Is(err, errMissingPage), errors.Is(err, store.ErrInvalidPage):
+		return true
+	default:
+		return false
 	}
-
-	r.ID = id
-
-	var hgetAllCmd *redis.StringStringMapCmd
-	var listCmds [3]*redis.StringSliceCmd
-
-	_, err := c.Pipelined(func(pipe redis.Pipeliner) error {
-		hgetAllCmd = pipe.HGetAll(fmt.Sprintf("recipe:%d", r.ID))
-
-		for i, l := range []string{"categories", "ingredients", "images"} {
-			listCmds[i] = pipe.LRange(fmt.Sprintf("recipe:%d:%s", r.ID, l), 0, -1)
-		}
-		return nil
-	})
-	if err != nil {
-		return err
-	}
-
-	result, err := hgetAllCmd.Result()
-	if err != nil {
-		return err
-	}
-	r.Title = result["title"]
-	r.Difficulty = result["difficulty"]
-	r.PrepPeriod = result["prep_period"]
-	r.Method = result["method"]
-
-	loadList := func(list ...*[]string) error {
-		for i := range list {
-			strings, err := listCmds[i].Result()
-			if err != nil {
-				return err
-			}
-			*list[i] = strings
-		}
-		return nil
-	}
-	err = loadList(&r.Categories, &r.Ingredients, &r.Images)
-	if err != nil {
-		return err
-	}
-
-	return nil
-}
-
-func list(page int, c *redis.Client) ([]recipe, error) {
-	if page <= 0 {
-		return nil, errors.New("invalid page")
-	}
-
-	const pageSize int64 = 20
-	from, to := (int64(page)-1)*pageSize, int64(page)*pageSize-1
-
-	recipeIds, err := c.LRange("recipes", from, to).Result()
-	if err != nil {
-		return nil, err
-	}
-
-	var cmds []*redis.SliceCmd
-	_, err = c.Pipelined(func(pipe redis.Pipeliner) error {
-		for _, recipeId := range recipeIds {
-			cmds = append(cmds, pipe.HMGet(fmt.Sprintf("recipe:%s", recipeId), "id", "title"))
-		}
-		return nil
-	})
-	if err != nil {
-		return nil, err
-	}
-
-	var titles []recipe
-	for _, c := range cmds {
-		id, _ := strconv.Atoi(c.Val()[0].(string))
-		titles = append(titles, recipe{
-			ID:    int64(id),
-			Title: c.Val()[1].(string),
-		})
-	}
-
-	return titles, nil
 }
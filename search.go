@@ -0,0 +1,39 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/thearyanahmed/RecipeAPI/store"
+)
+
+// SearchHandler serves GET /recipes/search?q=...&category=...&difficulty=...&page=...
+func SearchHandler(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	page := 1
+	if p := q.Get("page"); p != "" {
+		parsed, err := strconv.Atoi(p)
+		if err != nil {
+			handleError(w, err)
+			return
+		}
+		page = parsed
+	}
+
+	result, err := recipes.Search(r.Context(), store.SearchFilters{
+		Text:       q.Get("q"),
+		Category:   q.Get("category"),
+		Difficulty: q.Get("difficulty"),
+		Page:       page,
+	})
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	sendResponse(w, http.StatusOK, "ok", map[string]interface{}{
+		"total": result.Total,
+		"hits":  result.Recipes,
+	})
+}
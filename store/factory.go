@@ -0,0 +1,28 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// New builds the RecipeStore selected by the STORAGE_BACKEND env var
+// ("redis", "postgres" or "memory"; defaults to "redis").
+func New(ctx context.Context) (RecipeStore, error) {
+	switch backend := os.Getenv("STORAGE_BACKEND"); backend {
+	case "", "redis":
+		insecure, _ := strconv.ParseBool(os.Getenv("REDIS_INSECURE_SKIP_VERIFY"))
+		return NewRedisStore(ctx, os.Getenv("REDIS_HOST"), os.Getenv("REDIS_PASSWORD"), insecure)
+	case "postgres":
+		dsn := os.Getenv("DATABASE_URL")
+		if dsn == "" {
+			return nil, fmt.Errorf("DATABASE_URL must be set when STORAGE_BACKEND=postgres")
+		}
+		return NewPostgresStore(dsn)
+	case "memory":
+		return NewMemoryStore(), nil
+	default:
+		return nil, fmt.Errorf("unknown STORAGE_BACKEND %q", backend)
+	}
+}
@@ -0,0 +1,164 @@
+package store
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// MemoryStore is an in-memory RecipeStore, primarily intended for tests
+// where a live Redis instance isn't available or desirable.
+type MemoryStore struct {
+	mu      sync.Mutex
+	recipes map[int64]Recipe
+	nextID  int64
+}
+
+// NewMemoryStore returns an empty MemoryStore ready to use.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{recipes: make(map[int64]Recipe)}
+}
+
+func (s *MemoryStore) Save(ctx context.Context, r *Recipe, update bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, exists := s.recipes[r.ID]
+
+	if update {
+		if r.ID == 0 || !exists {
+			return ErrNotFound
+		}
+	} else if r.ID == 0 {
+		s.nextID++
+		r.ID = s.nextID
+	} else if exists {
+		return ErrDuplicate
+	}
+
+	s.recipes[r.ID] = *r
+	return nil
+}
+
+func (s *MemoryStore) Load(ctx context.Context, id int64) (*Recipe, error) {
+	if id <= 0 {
+		return nil, ErrInvalidID
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r, ok := s.recipes[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &r, nil
+}
+
+func (s *MemoryStore) List(ctx context.Context, page int) ([]Recipe, error) {
+	if page <= 0 {
+		return nil, ErrInvalidPage
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ids := make([]int64, 0, len(s.recipes))
+	for id := range s.recipes {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	from, to := (page-1)*PageSize, page*PageSize
+	if from > len(ids) {
+		from = len(ids)
+	}
+	if to > len(ids) {
+		to = len(ids)
+	}
+
+	var recipes []Recipe
+	for _, id := range ids[from:to] {
+		r := s.recipes[id]
+		recipes = append(recipes, Recipe{ID: r.ID, Title: r.Title})
+	}
+
+	return recipes, nil
+}
+
+func (s *MemoryStore) Search(ctx context.Context, filters SearchFilters) (SearchResult, error) {
+	if filters.Page <= 0 {
+		return SearchResult{}, ErrInvalidPage
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ids := make([]int64, 0, len(s.recipes))
+	for id := range s.recipes {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	var matches []Recipe
+	for _, id := range ids {
+		r := s.recipes[id]
+		if matchesFilters(r, filters) {
+			matches = append(matches, r)
+		}
+	}
+
+	total := len(matches)
+	from, to := (filters.Page-1)*PageSize, filters.Page*PageSize
+	if from > total {
+		from = total
+	}
+	if to > total {
+		to = total
+	}
+
+	return SearchResult{Recipes: matches[from:to], Total: total}, nil
+}
+
+func matchesFilters(r Recipe, filters SearchFilters) bool {
+	if filters.Text != "" {
+		text := strings.ToLower(filters.Text)
+		haystack := strings.ToLower(strings.Join([]string{r.Title, r.Method, strings.Join(r.Ingredients, " ")}, " "))
+		if !strings.Contains(haystack, text) {
+			return false
+		}
+	}
+	if filters.Category != "" && !containsFold(r.Categories, filters.Category) {
+		return false
+	}
+	if filters.Difficulty != "" && !strings.EqualFold(r.Difficulty, filters.Difficulty) {
+		return false
+	}
+	return true
+}
+
+func containsFold(haystack []string, needle string) bool {
+	for _, v := range haystack {
+		if strings.EqualFold(v, needle) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *MemoryStore) Delete(ctx context.Context, id int64) error {
+	if id <= 0 {
+		return ErrInvalidID
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.recipes[id]; !ok {
+		return ErrNotFound
+	}
+
+	delete(s.recipes, id)
+	return nil
+}
@@ -0,0 +1,256 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	_ "github.com/lib/pq"
+)
+
+// PostgresStore persists recipes in a `recipes` table, with `recipe_categories`,
+// `recipe_ingredients` and `recipe_images` join tables for the list fields.
+type PostgresStore struct {
+	db *sql.DB
+}
+
+// NewPostgresStore opens a connection pool to dsn. The schema is expected to
+// already exist; apply store/schema.postgres.sql against dsn's database before
+// using this backend.
+func NewPostgresStore(dsn string) (*PostgresStore, error) {
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Ping(); err != nil {
+		return nil, err
+	}
+	return &PostgresStore{db: db}, nil
+}
+
+func (s *PostgresStore) Save(ctx context.Context, r *Recipe, update bool) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	switch {
+	case update:
+		if r.ID == 0 {
+			return ErrNotFound
+		}
+		res, err := tx.ExecContext(ctx,
+			`UPDATE recipes SET title = $1, difficulty = $2, prep_period = $3, method = $4 WHERE id = $5`,
+			r.Title, r.Difficulty, r.PrepPeriod, r.Method, r.ID,
+		)
+		if err != nil {
+			return err
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return ErrNotFound
+		}
+	case r.ID == 0:
+		err = tx.QueryRowContext(ctx,
+			`INSERT INTO recipes (title, difficulty, prep_period, method) VALUES ($1, $2, $3, $4) RETURNING id`,
+			r.Title, r.Difficulty, r.PrepPeriod, r.Method,
+		).Scan(&r.ID)
+		if err != nil {
+			return err
+		}
+	default:
+		var exists bool
+		if err := tx.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM recipes WHERE id = $1)`, r.ID).Scan(&exists); err != nil {
+			return err
+		}
+		if exists {
+			return ErrDuplicate
+		}
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO recipes (id, title, difficulty, prep_period, method) VALUES ($1, $2, $3, $4, $5)`,
+			r.ID, r.Title, r.Difficulty, r.PrepPeriod, r.Method,
+		); err != nil {
+			return err
+		}
+	}
+
+	if err := replacePostgresList(ctx, tx, "recipe_categories", "category", r.ID, r.Categories); err != nil {
+		return err
+	}
+	if err := replacePostgresList(ctx, tx, "recipe_ingredients", "ingredient", r.ID, r.Ingredients); err != nil {
+		return err
+	}
+	if err := replacePostgresList(ctx, tx, "recipe_images", "image", r.ID, r.Images); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// replacePostgresList deletes and re-inserts the rows of a join table for recipeID.
+func replacePostgresList(ctx context.Context, tx *sql.Tx, table, column string, recipeID int64, values []string) error {
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf(`DELETE FROM %s WHERE recipe_id = $1`, table), recipeID); err != nil {
+		return err
+	}
+	for _, v := range values {
+		if _, err := tx.ExecContext(ctx,
+			fmt.Sprintf(`INSERT INTO %s (recipe_id, %s) VALUES ($1, $2)`, table, column),
+			recipeID, v,
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *PostgresStore) Load(ctx context.Context, id int64) (*Recipe, error) {
+	if id <= 0 {
+		return nil, ErrInvalidID
+	}
+
+	r := &Recipe{ID: id}
+	err := s.db.QueryRowContext(ctx,
+		`SELECT title, difficulty, prep_period, method FROM recipes WHERE id = $1`, id,
+	).Scan(&r.Title, &r.Difficulty, &r.PrepPeriod, &r.Method)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var err2 error
+	if r.Categories, err2 = s.loadList(ctx, "recipe_categories", "category", id); err2 != nil {
+		return nil, err2
+	}
+	if r.Ingredients, err2 = s.loadList(ctx, "recipe_ingredients", "ingredient", id); err2 != nil {
+		return nil, err2
+	}
+	if r.Images, err2 = s.loadList(ctx, "recipe_images", "image", id); err2 != nil {
+		return nil, err2
+	}
+
+	return r, nil
+}
+
+func (s *PostgresStore) loadList(ctx context.Context, table, column string, recipeID int64) ([]string, error) {
+	rows, err := s.db.QueryContext(ctx, fmt.Sprintf(`SELECT %s FROM %s WHERE recipe_id = $1 ORDER BY id`, column, table), recipeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var values []string
+	for rows.Next() {
+		var v string
+		if err := rows.Scan(&v); err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+	}
+	return values, rows.Err()
+}
+
+func (s *PostgresStore) List(ctx context.Context, page int) ([]Recipe, error) {
+	if page <= 0 {
+		return nil, ErrInvalidPage
+	}
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT id, title FROM recipes ORDER BY id LIMIT $1 OFFSET $2`,
+		PageSize, (page-1)*PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var recipes []Recipe
+	for rows.Next() {
+		var r Recipe
+		if err := rows.Scan(&r.ID, &r.Title); err != nil {
+			return nil, err
+		}
+		recipes = append(recipes, r)
+	}
+	return recipes, rows.Err()
+}
+
+func (s *PostgresStore) Search(ctx context.Context, filters SearchFilters) (SearchResult, error) {
+	if filters.Page <= 0 {
+		return SearchResult{}, ErrInvalidPage
+	}
+
+	var where []string
+	var args []interface{}
+
+	if filters.Text != "" {
+		args = append(args, "%"+filters.Text+"%")
+		where = append(where, fmt.Sprintf(`(recipes.title ILIKE $%d OR recipes.method ILIKE $%d)`, len(args), len(args)))
+	}
+	if filters.Difficulty != "" {
+		args = append(args, filters.Difficulty)
+		where = append(where, fmt.Sprintf(`recipes.difficulty = $%d`, len(args)))
+	}
+	if filters.Category != "" {
+		args = append(args, filters.Category)
+		where = append(where, fmt.Sprintf(`EXISTS (SELECT 1 FROM recipe_categories rc WHERE rc.recipe_id = recipes.id AND rc.category = $%d)`, len(args)))
+	}
+
+	whereClause := ""
+	if len(where) > 0 {
+		whereClause = "WHERE " + strings.Join(where, " AND ")
+	}
+
+	var total int
+	countQuery := fmt.Sprintf(`SELECT count(*) FROM recipes %s`, whereClause)
+	if err := s.db.QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
+		return SearchResult{}, err
+	}
+
+	args = append(args, PageSize, (filters.Page-1)*PageSize)
+	listQuery := fmt.Sprintf(`SELECT id, title FROM recipes %s ORDER BY id LIMIT $%d OFFSET $%d`, whereClause, len(args)-1, len(args))
+	rows, err := s.db.QueryContext(ctx, listQuery, args...)
+	if err != nil {
+		return SearchResult{}, err
+	}
+	defer rows.Close()
+
+	var recipes []Recipe
+	for rows.Next() {
+		var r Recipe
+		if err := rows.Scan(&r.ID, &r.Title); err != nil {
+			return SearchResult{}, err
+		}
+		recipes = append(recipes, r)
+	}
+	if err := rows.Err(); err != nil {
+		return SearchResult{}, err
+	}
+
+	return SearchResult{Recipes: recipes, Total: total}, nil
+}
+
+func (s *PostgresStore) Delete(ctx context.Context, id int64) error {
+	if id <= 0 {
+		return ErrInvalidID
+	}
+
+	res, err := s.db.ExecContext(ctx, `DELETE FROM recipes WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
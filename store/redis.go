@@ -0,0 +1,338 @@
+package store
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/go-redis/redis/v8"
+)
+
+const searchIndexName = "recipes-idx"
+
+// RedisStore persists recipes as Redis hashes and lists, and serves Search
+// through a RediSearch index maintained alongside the hash.
+type RedisStore struct {
+	c *redis.Client
+}
+
+// NewRedisStore dials Redis at addr and ensures the RediSearch index exists.
+func NewRedisStore(ctx context.Context, addr, password string, insecureSkipVerify bool) (*RedisStore, error) {
+	c := redis.NewClient(&redis.Options{
+		Addr:     addr,
+		Password: password,
+		TLSConfig: &tls.Config{
+			InsecureSkipVerify: insecureSkipVerify, // Only for self-signed certificates
+		},
+	})
+
+	s := &RedisStore{c: c}
+	if err := s.ensureSearchIndex(ctx); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Ping verifies connectivity to Redis; used by the health subsystem.
+func (s *RedisStore) Ping(ctx context.Context) error {
+	return s.c.Ping(ctx).Err()
+}
+
+func (s *RedisStore) ensureSearchIndex(ctx context.Context) error {
+	err := s.c.Do(ctx, "FT.CREATE", searchIndexName,
+		"ON", "HASH",
+		"PREFIX", "1", "recipe:",
+		"SCHEMA",
+		"title", "TEXT",
+		"method", "TEXT",
+		"ingredients", "TEXT",
+		"categories", "TAG",
+		"difficulty", "TAG",
+	).Err()
+	if err != nil && strings.Contains(err.Error(), "Index already exists") {
+		return nil
+	}
+	return err
+}
+
+func (s *RedisStore) Save(ctx context.Context, r *Recipe, update bool) error {
+	if update {
+		if r.ID == 0 {
+			return ErrNotFound
+		}
+		exists, err := s.c.Exists(ctx, fmt.Sprintf("recipe:%d", r.ID)).Result()
+		if err != nil {
+			return err
+		}
+		if exists == 0 {
+			return ErrNotFound
+		}
+	} else if r.ID == 0 {
+		id, err := s.c.Incr(ctx, "recipe_id").Result()
+		if err != nil {
+			return err
+		}
+		r.ID = id
+	} else {
+		exists, err := s.c.Exists(ctx, fmt.Sprintf("recipe:%d", r.ID)).Result()
+		if err != nil {
+			return err
+		}
+		if exists == 1 {
+			return ErrDuplicate
+		}
+	}
+
+	create := !update
+
+	_, err := s.c.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		if create {
+			if err := pipe.RPush(ctx, "recipes", r.ID).Err(); err != nil {
+				return err
+			}
+		}
+		pipe.HMSet(ctx, fmt.Sprintf("recipe:%d", r.ID),
+			"id", r.ID,
+			"title", r.Title,
+			"difficulty", r.Difficulty,
+			"prep_period", r.PrepPeriod,
+			"method", r.Method,
+			"categories", strings.Join(r.Categories, ","),
+			"ingredients", strings.Join(r.Ingredients, ","),
+		)
+
+		replaceList := func(name string, values []string) error {
+			key := fmt.Sprintf("recipe:%d:%s", r.ID, name)
+			if !create {
+				pipe.Del(ctx, key)
+			}
+			if len(values) == 0 {
+				return nil
+			}
+			return pipe.RPush(ctx, key, values).Err()
+		}
+
+		if err := replaceList("categories", r.Categories); err != nil {
+			return err
+		}
+		if err := replaceList("ingredients", r.Ingredients); err != nil {
+			return err
+		}
+		if err := replaceList("images", r.Images); err != nil {
+			return err
+		}
+
+		return nil
+	})
+
+	return err
+}
+
+func (s *RedisStore) Load(ctx context.Context, id int64) (*Recipe, error) {
+	if id <= 0 {
+		return nil, ErrInvalidID
+	}
+
+	r := &Recipe{ID: id}
+
+	var hgetAllCmd *redis.StringStringMapCmd
+	var listCmds [3]*redis.StringSliceCmd
+
+	_, err := s.c.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		hgetAllCmd = pipe.HGetAll(ctx, fmt.Sprintf("recipe:%d", r.ID))
+
+		for i, l := range []string{"categories", "ingredients", "images"} {
+			listCmds[i] = pipe.LRange(ctx, fmt.Sprintf("recipe:%d:%s", r.ID, l), 0, -1)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := hgetAllCmd.Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(result) == 0 {
+		return nil, ErrNotFound
+	}
+	r.Title = result["title"]
+	r.Difficulty = result["difficulty"]
+	r.PrepPeriod = result["prep_period"]
+	r.Method = result["method"]
+
+	loadList := func(list ...*[]string) error {
+		for i := range list {
+			strs, err := listCmds[i].Result()
+			if err != nil {
+				return err
+			}
+			*list[i] = strs
+		}
+		return nil
+	}
+	if err := loadList(&r.Categories, &r.Ingredients, &r.Images); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func (s *RedisStore) List(ctx context.Context, page int) ([]Recipe, error) {
+	if page <= 0 {
+		return nil, ErrInvalidPage
+	}
+
+	const pageSize int64 = PageSize
+	from, to := (int64(page)-1)*pageSize, int64(page)*pageSize-1
+
+	recipeIds, err := s.c.LRange(ctx, "recipes", from, to).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	var cmds []*redis.SliceCmd
+	_, err = s.c.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, recipeId := range recipeIds {
+			cmds = append(cmds, pipe.HMGet(ctx, fmt.Sprintf("recipe:%s", recipeId), "id", "title"))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var recipes []Recipe
+	for _, cmd := range cmds {
+		id, _ := strconv.Atoi(cmd.Val()[0].(string))
+		recipes = append(recipes, Recipe{
+			ID:    int64(id),
+			Title: cmd.Val()[1].(string),
+		})
+	}
+
+	return recipes, nil
+}
+
+func (s *RedisStore) Search(ctx context.Context, filters SearchFilters) (SearchResult, error) {
+	page := filters.Page
+	if page <= 0 {
+		return SearchResult{}, ErrInvalidPage
+	}
+
+	const pageSize = PageSize
+	offset := (page - 1) * pageSize
+
+	var clauses []string
+	if filters.Text != "" {
+		clauses = append(clauses, fmt.Sprintf("@title|method|ingredients:(%s)", escapeSearchTerm(filters.Text)))
+	}
+	if filters.Category != "" {
+		clauses = append(clauses, fmt.Sprintf("@categories:{%s}", escapeSearchTerm(filters.Category)))
+	}
+	if filters.Difficulty != "" {
+		clauses = append(clauses, fmt.Sprintf("@difficulty:{%s}", escapeSearchTerm(filters.Difficulty)))
+	}
+
+	query := "*"
+	if len(clauses) > 0 {
+		query = strings.Join(clauses, " ")
+	}
+
+	res, err := s.c.Do(ctx, "FT.SEARCH", searchIndexName, query,
+		"LIMIT", offset, pageSize,
+	).Result()
+	if err != nil {
+		return SearchResult{}, err
+	}
+
+	recipes, total, err := parseSearchResult(res)
+	if err != nil {
+		return SearchResult{}, err
+	}
+
+	return SearchResult{Recipes: recipes, Total: total}, nil
+}
+
+func (s *RedisStore) Delete(ctx context.Context, id int64) error {
+	if id <= 0 {
+		return ErrInvalidID
+	}
+
+	exists, err := s.c.Exists(ctx, fmt.Sprintf("recipe:%d", id)).Result()
+	if err != nil {
+		return err
+	}
+	if exists == 0 {
+		return ErrNotFound
+	}
+
+	_, err = s.c.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx,
+			fmt.Sprintf("recipe:%d", id),
+			fmt.Sprintf("recipe:%d:categories", id),
+			fmt.Sprintf("recipe:%d:ingredients", id),
+			fmt.Sprintf("recipe:%d:images", id),
+		)
+		pipe.LRem(ctx, "recipes", 0, id)
+		return nil
+	})
+	return err
+}
+
+// escapeSearchTerm neutralises RediSearch special characters in user input.
+func escapeSearchTerm(s string) string {
+	replacer := strings.NewReplacer(
+		"-", "\\-", "@", "\\@", "(", "\\(", ")", "\\)",
+		"{", "\\{", "}", "\\}", "|", "\\|",
+	)
+	return replacer.Replace(s)
+}
+
+// parseSearchResult turns the raw FT.SEARCH reply ([total, id, fields, id,
+// fields, ...]) into recipes.
+func parseSearchResult(res interface{}) ([]Recipe, int, error) {
+	rows, ok := res.([]interface{})
+	if !ok || len(rows) == 0 {
+		return nil, 0, nil
+	}
+
+	total, _ := rows[0].(int64)
+
+	var recipes []Recipe
+	for i := 1; i+1 < len(rows); i += 2 {
+		idKey, _ := rows[i].(string)
+		fields, _ := rows[i+1].([]interface{})
+
+		rec := Recipe{}
+		if idx := strings.LastIndex(idKey, ":"); idx != -1 {
+			if id, err := strconv.Atoi(idKey[idx+1:]); err == nil {
+				rec.ID = int64(id)
+			}
+		}
+
+		for j := 0; j+1 < len(fields); j += 2 {
+			key, _ := fields[j].(string)
+			val, _ := fields[j+1].(string)
+			switch key {
+			case "title":
+				rec.Title = val
+			case "method":
+				rec.Method = val
+			case "difficulty":
+				rec.Difficulty = val
+			case "categories":
+				rec.Categories = strings.Split(val, ",")
+			case "ingredients":
+				rec.Ingredients = strings.Split(val, ",")
+			}
+		}
+
+		recipes = append(recipes, rec)
+	}
+
+	return recipes, int(total), nil
+}
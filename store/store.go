@@ -0,0 +1,68 @@
+// Package store defines the persistence boundary for recipes and provides
+// Redis, in-memory and Postgres implementations behind a single interface.
+package store
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrNotFound is returned when the requested recipe does not exist.
+var ErrNotFound = errors.New("recipe not found")
+
+// ErrDuplicate is returned when creating a recipe that already exists.
+var ErrDuplicate = errors.New("recipe already exists")
+
+// ErrInvalidID is returned when an id is not a positive integer.
+var ErrInvalidID = errors.New("invalid id")
+
+// ErrInvalidPage is returned when a page number is not a positive integer.
+var ErrInvalidPage = errors.New("invalid page")
+
+// Recipe is the domain model persisted by a RecipeStore.
+type Recipe struct {
+	ID          int64    `json:"id"`
+	Title       string   `json:"title"`
+	Difficulty  string   `json:"difficulty,omitempty"`
+	PrepPeriod  string   `json:"prep_period,omitempty"`
+	Method      string   `json:"method,omitempty"`
+	Categories  []string `json:"categories,omitempty"`
+	Ingredients []string `json:"ingredients,omitempty"`
+	Images      []string `json:"images,omitempty"`
+}
+
+// SearchFilters narrows a Search call to recipes matching every non-zero
+// field. An empty Text/Category/Difficulty means "don't filter on this".
+type SearchFilters struct {
+	Text       string
+	Category   string
+	Difficulty string
+	Page       int
+}
+
+// SearchResult is a page of recipes alongside the total number of matches.
+type SearchResult struct {
+	Recipes []Recipe
+	Total   int
+}
+
+// RecipeStore is the persistence boundary handlers depend on, so that they
+// can be unit tested without a live backend and so the backend itself can
+// be swapped via STORAGE_BACKEND without touching handler code.
+type RecipeStore interface {
+	// Save creates r when update is false, otherwise overwrites the
+	// existing recipe identified by r.ID. On create, any caller-supplied
+	// r.ID is honoured but must not already exist (ErrDuplicate); on
+	// update, r.ID must already exist (ErrNotFound).
+	Save(ctx context.Context, r *Recipe, update bool) error
+	// Load returns the recipe identified by id, or ErrNotFound.
+	Load(ctx context.Context, id int64) (*Recipe, error)
+	// List returns a page of recipe summaries (id + title).
+	List(ctx context.Context, page int) ([]Recipe, error)
+	// Search returns recipes matching filters, paged.
+	Search(ctx context.Context, filters SearchFilters) (SearchResult, error)
+	// Delete removes the recipe identified by id and all associated data.
+	Delete(ctx context.Context, id int64) error
+}
+
+const PageSize = 20